@@ -0,0 +1,209 @@
+package deploy
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+
+	"github.com/spf13/afero"
+	"github.com/supabase/cli/internal/utils/output"
+)
+
+const (
+	eszipContentType = "application/vnd.denoland.eszip"
+	zipContentType   = "application/zip"
+	archiveManifest  = "supabase.json"
+)
+
+// archiveManifestEntry describes one function declared in a multi-slug
+// archive's top-level supabase.json manifest.
+type archiveManifestEntry struct {
+	Slug       string `json:"slug"`
+	Entrypoint string `json:"entrypoint"`
+	ImportMap  string `json:"import_map"`
+	VerifyJWT  *bool  `json:"verify_jwt"`
+}
+
+// deployArchive deploys a single function from a pre-built ESZIP or ZIP
+// archive, skipping the Deno bundler entirely. entrypoint is the path to the
+// function's entrypoint inside the archive, defaulting to "index.ts".
+func deployArchive(ctx context.Context, slug, projectRef, archivePath, entrypoint, importMap string, noVerifyJWT *bool, fsys afero.Fs) (id, action string, err error) {
+	if !funcSlugPattern.MatchString(slug) {
+		return "", "", fmt.Errorf("Invalid Function name. %q must follow Deno module name convention: https://deno.land/manual/examples/import_map", slug)
+	}
+	if entrypoint == "" {
+		entrypoint = "index.ts"
+	}
+
+	data, err := afero.ReadFile(fsys, archivePath)
+	if err != nil {
+		return "", "", err
+	}
+	contentType := archiveContentType(data)
+	if contentType == zipContentType {
+		if err := verifyZipEntrypoint(data, entrypoint); err != nil {
+			return "", "", err
+		}
+	}
+	// ESZIP archives are Deno's compiled module graph format, not a zip
+	// directory listing, so there's no membership check to run here: the
+	// entrypoint is already baked into the graph by whatever built the
+	// archive, and verifyZipEntrypoint has nothing to open against.
+
+	verifyJWT := resolveVerifyJWT(slug, noVerifyJWT)
+	return deployFunction(ctx, projectRef, slug, archiveRef(slug, entrypoint), importMap, verifyJWT, contentType, bytes.NewReader(data))
+}
+
+// archiveRef builds the canonical entrypoint_path/import_map_path URI for a
+// path that lives inside an archive, matching the file://, https://, and
+// github:// schemes loader.Resolve reports for the other source kinds so
+// consumers can rely on these fields always being a URI, never a bare
+// in-archive path.
+func archiveRef(slug, archivePath string) string {
+	if archivePath == "" {
+		return ""
+	}
+	return "archive://" + slug + "/" + path.Clean(archivePath)
+}
+
+// deployArchiveManifest deploys every function declared in a multi-slug
+// archive's top-level supabase.json manifest, reusing the same archive body
+// for each function and pointing entrypoint_path/import_map_path at the
+// paths declared for that slug inside the archive. Results are rendered in
+// format the same way deployAll renders the bundler path's results.
+func deployArchiveManifest(ctx context.Context, archivePath, projectRef string, failFast bool, format output.Format, fsys afero.Fs) error {
+	data, err := afero.ReadFile(fsys, archivePath)
+	if err != nil {
+		return err
+	}
+	entries, err := readArchiveManifest(data)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	var firstErr error
+	records := make([]deployRecord, 0, len(entries))
+	for _, entry := range entries {
+		id, action, err := deployManifestEntry(ctx, entry, projectRef, data)
+		rec := deployRecord{Slug: entry.Slug, Id: id, Action: action}
+		if err != nil {
+			err = fmt.Errorf("%s: %w", entry.Slug, err)
+			rec.Action, rec.Error = actionFailed, err.Error()
+			records = append(records, rec)
+			if failFast {
+				firstErr = err
+				break
+			}
+			errs = append(errs, err)
+			continue
+		}
+		records = append(records, rec)
+	}
+	if err := output.Write(os.Stdout, format, records, func() error { return nil }); err != nil {
+		return err
+	}
+
+	if firstErr != nil {
+		return firstErr
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errors.Join(errs...)
+}
+
+// deployArchives deploys each slug's pre-built archive independently, as
+// supplied via repeated --file slug=path flags, rendering results in format
+// the same way deployAll renders the bundler path's results.
+func deployArchives(ctx context.Context, archives map[string]string, projectRef, importMapPath string, noVerifyJWT *bool, failFast bool, format output.Format, fsys afero.Fs) error {
+	var errs []error
+	var firstErr error
+	records := make([]deployRecord, 0, len(archives))
+	for slug, archivePath := range archives {
+		id, action, err := deployArchive(ctx, slug, projectRef, archivePath, "", importMapPath, noVerifyJWT, fsys)
+		rec := deployRecord{Slug: slug, Id: id, Action: action}
+		if err != nil {
+			err = fmt.Errorf("%s: %w", slug, err)
+			rec.Action, rec.Error = actionFailed, err.Error()
+			records = append(records, rec)
+			if failFast {
+				firstErr = err
+				break
+			}
+			errs = append(errs, err)
+			continue
+		}
+		records = append(records, rec)
+	}
+	if err := output.Write(os.Stdout, format, records, func() error { return nil }); err != nil {
+		return err
+	}
+
+	if firstErr != nil {
+		return firstErr
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errors.Join(errs...)
+}
+
+func deployManifestEntry(ctx context.Context, entry archiveManifestEntry, projectRef string, data []byte) (id, action string, err error) {
+	if err := verifyZipEntrypoint(data, entry.Entrypoint); err != nil {
+		return "", "", err
+	}
+	verifyJWT := true
+	if entry.VerifyJWT != nil {
+		verifyJWT = *entry.VerifyJWT
+	}
+	return deployFunction(ctx, projectRef, entry.Slug, archiveRef(entry.Slug, entry.Entrypoint), archiveRef(entry.Slug, entry.ImportMap), verifyJWT, zipContentType, bytes.NewReader(data))
+}
+
+func readArchiveManifest(data []byte) ([]archiveManifestEntry, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+	f, err := zr.Open(archiveManifest)
+	if err != nil {
+		return nil, fmt.Errorf("archive is missing a top-level %s manifest: %w", archiveManifest, err)
+	}
+	defer f.Close()
+	raw, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+	var entries []archiveManifestEntry
+	if err := json.Unmarshal(raw, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func verifyZipEntrypoint(data []byte, entrypoint string) error {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return err
+	}
+	clean := path.Clean(entrypoint)
+	for _, f := range zr.File {
+		if path.Clean(f.Name) == clean {
+			return nil
+		}
+	}
+	return fmt.Errorf("entrypoint %q not found in archive", entrypoint)
+}
+
+func archiveContentType(data []byte) string {
+	if len(data) >= 2 && data[0] == 'P' && data[1] == 'K' {
+		return zipContentType
+	}
+	return eszipContentType
+}
@@ -0,0 +1,132 @@
+package deploy
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/supabase/cli/internal/testing/apitest"
+	"github.com/supabase/cli/internal/utils"
+	"github.com/supabase/cli/internal/utils/output"
+	"github.com/supabase/cli/pkg/api"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func writeZipArchive(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range files {
+		f, err := w.Create(name)
+		require.NoError(t, err)
+		_, err = f.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func TestDeployArchive(t *testing.T) {
+	const slug = "test-func"
+
+	t.Run("deploys a single-slug zip archive", func(t *testing.T) {
+		fsys := afero.NewMemMapFs()
+		project := apitest.RandomProjectRef()
+		token := apitest.RandomAccessToken(t)
+		t.Setenv("SUPABASE_ACCESS_TOKEN", string(token))
+		archivePath := "/tmp/func.zip"
+		require.NoError(t, afero.WriteFile(fsys, archivePath, writeZipArchive(t, map[string]string{"index.ts": "export default () => new Response('ok')"}), 0644))
+		defer gock.OffAll()
+		gock.New(utils.DefaultApiHost).
+			Get("/v1/projects/" + project + "/functions/" + slug).
+			Reply(http.StatusNotFound)
+		gock.New(utils.DefaultApiHost).
+			Post("/v1/projects/"+project+"/functions").
+			MatchParam("entrypoint_path", "archive://"+slug+"/index.ts").
+			Reply(http.StatusCreated).
+			JSON(api.FunctionResponse{Id: "1"})
+		// Run test
+		noVerifyJWT := true
+		_, _, err := deployArchive(context.Background(), slug, project, archivePath, "", "", &noVerifyJWT, fsys)
+		// Check error
+		assert.NoError(t, err)
+		assert.Empty(t, apitest.ListUnmatchedRequests())
+	})
+
+	t.Run("throws error when the declared entrypoint is missing", func(t *testing.T) {
+		fsys := afero.NewMemMapFs()
+		project := apitest.RandomProjectRef()
+		archivePath := "/tmp/func.zip"
+		require.NoError(t, afero.WriteFile(fsys, archivePath, writeZipArchive(t, map[string]string{"other.ts": ""}), 0644))
+		// Run test
+		_, _, err := deployArchive(context.Background(), slug, project, archivePath, "index.ts", "", nil, fsys)
+		// Check error
+		assert.ErrorContains(t, err, "entrypoint \"index.ts\" not found in archive")
+	})
+
+	t.Run("throws error on malformed slug", func(t *testing.T) {
+		fsys := afero.NewMemMapFs()
+		project := apitest.RandomProjectRef()
+		// Run test
+		_, _, err := deployArchive(context.Background(), "@", project, "/tmp/func.zip", "", "", nil, fsys)
+		// Check error
+		assert.ErrorContains(t, err, "Invalid Function name.")
+	})
+}
+
+func TestDeployArchiveManifest(t *testing.T) {
+	t.Run("deploys every function declared in the manifest", func(t *testing.T) {
+		fsys := afero.NewMemMapFs()
+		project := apitest.RandomProjectRef()
+		token := apitest.RandomAccessToken(t)
+		t.Setenv("SUPABASE_ACCESS_TOKEN", string(token))
+
+		entries := []archiveManifestEntry{
+			{Slug: "func-a", Entrypoint: "func-a/index.ts"},
+			{Slug: "func-b", Entrypoint: "func-b/index.ts"},
+		}
+		manifest, err := json.Marshal(entries)
+		require.NoError(t, err)
+		archivePath := "/tmp/bundle.zip"
+		require.NoError(t, afero.WriteFile(fsys, archivePath, writeZipArchive(t, map[string]string{
+			archiveManifest:     string(manifest),
+			"func-a/index.ts":   "",
+			"func-b/index.ts":   "",
+		}), 0644))
+
+		defer gock.OffAll()
+		for _, entry := range entries {
+			gock.New(utils.DefaultApiHost).
+				Get("/v1/projects/" + project + "/functions/" + entry.Slug).
+				Reply(http.StatusNotFound)
+			gock.New(utils.DefaultApiHost).
+				Post("/v1/projects/"+project+"/functions").
+				MatchParam("slug", entry.Slug).
+				MatchParam("entrypoint_path", "archive://"+entry.Slug+"/"+entry.Entrypoint).
+				Reply(http.StatusCreated).
+				JSON(api.FunctionResponse{Id: "1"})
+		}
+		// Run test
+		err = deployArchiveManifest(context.Background(), archivePath, project, false, output.FormatTable, fsys)
+		// Check error
+		assert.NoError(t, err)
+		assert.Empty(t, apitest.ListUnmatchedRequests())
+	})
+
+	t.Run("throws error when manifest is missing", func(t *testing.T) {
+		fsys := afero.NewMemMapFs()
+		project := apitest.RandomProjectRef()
+		archivePath := "/tmp/bundle.zip"
+		require.NoError(t, afero.WriteFile(fsys, archivePath, writeZipArchive(t, map[string]string{"index.ts": ""}), 0644))
+		// Run test
+		err := deployArchiveManifest(context.Background(), archivePath, project, false, output.FormatTable, fsys)
+		// Check error
+		assert.ErrorContains(t, err, "missing a top-level supabase.json manifest")
+	})
+}
@@ -0,0 +1,391 @@
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/supabase/cli/internal/utils"
+	"github.com/supabase/cli/internal/utils/output"
+	"github.com/supabase/cli/pkg/api"
+	"github.com/supabase/cli/pkg/loader"
+)
+
+const (
+	actionCreated = "created"
+	actionUpdated = "updated"
+	actionFailed  = "failed"
+)
+
+// deployRecord is the per-function result of a deploy, rendered via
+// internal/utils/output in whichever format --output requested.
+type deployRecord struct {
+	Slug   string `json:"slug" yaml:"slug"`
+	Id     string `json:"id,omitempty" yaml:"id,omitempty"`
+	Action string `json:"action" yaml:"action"`
+	Error  string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+var funcSlugPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// Run deploys the given Function slugs, or every Function found under
+// supabase/functions when slugs is empty, to the linked project. When watch
+// is set, Run keeps running after the initial deploy and redeploys
+// individual functions as their sources change on disk.
+//
+// archivePath, when set without archives, points at a single archive whose
+// top-level supabase.json manifest declares every function to deploy.
+// archives, when set, maps a slug to a pre-built archive for that function
+// alone (populated from repeated --file slug=path flags); it takes priority
+// over bundling with Deno and skips the manifest lookup entirely.
+func Run(ctx context.Context, slugs []string, projectRef string, noVerifyJWT *bool, importMapPath string, jobs int, failFast, watch bool, archivePath string, archives map[string]string, format output.Format, fsys afero.Fs) error {
+	if len(archives) > 0 {
+		return deployArchives(ctx, archives, projectRef, importMapPath, noVerifyJWT, failFast, format, fsys)
+	}
+	if archivePath != "" {
+		return deployArchiveManifest(ctx, archivePath, projectRef, failFast, format, fsys)
+	}
+
+	if err := utils.LoadConfigFS(fsys); err != nil {
+		return err
+	}
+	resolved, err := resolveFunctionSlugs(slugs, fsys)
+	if err != nil {
+		return err
+	}
+	if len(resolved) == 0 {
+		return errors.New("No Functions specified or found in supabase/functions")
+	}
+	if err := deployAll(ctx, resolved, projectRef, importMapPath, noVerifyJWT, jobs, failFast, format, fsys); err != nil {
+		if !watch {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Initial deploy failed, continuing in watch mode: %v\n", err)
+	}
+	if !watch {
+		return nil
+	}
+	return watchFunctions(ctx, resolved, projectRef, importMapPath, noVerifyJWT, jobs, failFast, fsys)
+}
+
+func resolveFunctionSlugs(slugs []string, fsys afero.Fs) ([]string, error) {
+	if len(slugs) > 0 {
+		return slugs, nil
+	}
+	paths, err := afero.Glob(fsys, filepath.Join(utils.FunctionsDir, "*"))
+	if err != nil {
+		return nil, err
+	}
+	var found []string
+	for _, path := range paths {
+		if isDir, err := afero.IsDir(fsys, path); err != nil {
+			return nil, err
+		} else if isDir {
+			found = append(found, filepath.Base(path))
+		}
+	}
+	return found, nil
+}
+
+// deployAll fans out deployOne across a bounded worker pool, keeping a live
+// status table up to date, and returns a joined error listing every slug
+// that failed to deploy. When failFast is set, the first failure cancels
+// the remaining work and only that error, prefixed with its slug, is
+// returned, matching the old behavior of bailing out on the first error.
+func deployAll(ctx context.Context, slugs []string, projectRef, importMapPath string, noVerifyJWT *bool, jobs int, failFast bool, format output.Format, fsys afero.Fs) error {
+	if jobs <= 0 {
+		jobs = runtime.NumCPU()
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	progress := newProgressTable(slugs)
+	stopRendering := progress.startRendering(os.Stderr, 200*time.Millisecond)
+	defer stopRendering()
+
+	sem := make(chan struct{}, jobs)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var errs []error
+	records := make([]deployRecord, len(slugs))
+
+	for i, slug := range slugs {
+		mu.Lock()
+		records[i] = deployRecord{Slug: slug}
+		mu.Unlock()
+		if err := ctx.Err(); err != nil {
+			progress.setState(slug, stateFailed, err)
+			mu.Lock()
+			records[i].Action, records[i].Error = actionFailed, err.Error()
+			errs = append(errs, fmt.Errorf("%s: %w", slug, err))
+			mu.Unlock()
+			continue
+		}
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(i int, slug string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if ctx.Err() != nil {
+				progress.setState(slug, stateFailed, ctx.Err())
+				mu.Lock()
+				records[i].Action, records[i].Error = actionFailed, ctx.Err().Error()
+				errs = append(errs, fmt.Errorf("%s: %w", slug, ctx.Err()))
+				mu.Unlock()
+				return
+			}
+			progress.setState(slug, stateBundling, nil)
+			id, action, err := deployOne(ctx, slug, projectRef, importMapPath, "", noVerifyJWT, progress, fsys)
+			mu.Lock()
+			records[i].Id, records[i].Action = id, action
+			mu.Unlock()
+			if err != nil {
+				progress.setState(slug, stateFailed, err)
+				mu.Lock()
+				records[i].Error = err.Error()
+				errs = append(errs, fmt.Errorf("%s: %w", slug, err))
+				mu.Unlock()
+				if failFast {
+					cancel()
+				}
+				return
+			}
+			progress.setState(slug, stateDone, nil)
+		}(i, slug)
+	}
+	wg.Wait()
+	stopRendering()
+	progress.render(os.Stderr)
+
+	// The live status table above already covers FormatTable; only the
+	// machine-readable formats need a final structured render here.
+	if err := output.Write(os.Stdout, format, records, func() error {
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	if failFast {
+		return errs[0]
+	}
+	return errors.Join(errs...)
+}
+
+// progress, when non-nil, is updated to stateUploading once bundling
+// finishes and the HTTP upload is about to start.
+func deployOne(ctx context.Context, slug, projectRef, importMapPath, envFilePath string, noVerifyJWT *bool, progress *progressTable, fsys afero.Fs) (id, action string, err error) {
+	if !funcSlugPattern.MatchString(slug) {
+		return "", "", fmt.Errorf("Invalid Function name. %q must follow Deno module name convention: https://deno.land/manual/examples/import_map", slug)
+	}
+
+	// supabase/config.toml may point a function's entrypoint at a remote
+	// https://, file://, or github://owner/repo@ref/path location instead of
+	// the default supabase/functions/<slug>/index.ts on disk.
+	entrypointRef := ""
+	if fc, ok := utils.Config.Functions[slug]; ok {
+		entrypointRef = fc.Entrypoint
+	}
+	if isRemoteRef(entrypointRef) || isRemoteRef(importMapPath) {
+		return deployRemote(ctx, slug, projectRef, entrypointRef, importMapPath, noVerifyJWT, progress, fsys)
+	}
+
+	if importMapPath == "" {
+		importMapPath = utils.FallbackImportMapPath
+	} else if exists, err := afero.Exists(fsys, importMapPath); err != nil {
+		return "", "", err
+	} else if !exists {
+		return "", "", fmt.Errorf("import map %s does not exist: %w", importMapPath, os.ErrNotExist)
+	}
+	importMapPath, err = filepath.Abs(importMapPath)
+	if err != nil {
+		return "", "", err
+	}
+
+	entrypointPath, err := filepath.Abs(filepath.Join(utils.FunctionsDir, slug, "index.ts"))
+	if err != nil {
+		return "", "", err
+	}
+
+	body, err := bundleFunction(ctx, entrypointPath, importMapPath, fsys)
+	if err != nil {
+		return "", "", err
+	}
+
+	verifyJWT := resolveVerifyJWT(slug, noVerifyJWT)
+
+	progress.setState(slug, stateUploading, nil)
+	return deployFunction(ctx, projectRef, slug, "file://"+entrypointPath, "file://"+importMapPath, verifyJWT, eszipContentType, body)
+}
+
+func isRemoteRef(ref string) bool {
+	return strings.HasPrefix(ref, "https://") || strings.HasPrefix(ref, "http://") || strings.HasPrefix(ref, "github://") || strings.HasPrefix(ref, "file://")
+}
+
+// deployRemote deploys a function whose entrypoint and/or import map were
+// declared as remote refs, resolving each through loader.Resolve and
+// bundling from a local copy so the Deno bundler keeps working with plain
+// file paths. The canonical URLs loader.Resolve reports are sent as
+// entrypoint_path/import_map_path so the project knows the original source,
+// even though the uploaded body was bundled from a cached local copy.
+func deployRemote(ctx context.Context, slug, projectRef, entrypointRef, importMapRef string, noVerifyJWT *bool, progress *progressTable, fsys afero.Fs) (id, action string, err error) {
+	if entrypointRef == "" {
+		return "", "", errors.New("a remote import map requires an entrypoint to bundle against")
+	}
+
+	entrypointPath, entrypointURL, cleanup, err := materializeSource(ctx, entrypointRef, fsys)
+	if err != nil {
+		return "", "", err
+	}
+	defer cleanup()
+
+	var importMapPath, importMapURL string
+	if importMapRef != "" {
+		var cleanupMap func()
+		importMapPath, importMapURL, cleanupMap, err = materializeSource(ctx, importMapRef, fsys)
+		if err != nil {
+			return "", "", err
+		}
+		defer cleanupMap()
+	}
+
+	body, err := bundleFunction(ctx, entrypointPath, importMapPath, fsys)
+	if err != nil {
+		return "", "", err
+	}
+
+	verifyJWT := resolveVerifyJWT(slug, noVerifyJWT)
+	progress.setState(slug, stateUploading, nil)
+	return deployFunction(ctx, projectRef, slug, entrypointURL, importMapURL, verifyJWT, eszipContentType, body)
+}
+
+// materializeSource resolves ref to a local file path the Deno bundler can
+// read, downloading and caching remote content via loader.Resolve first
+// where necessary, and returns the canonical URL to report back to the API
+// alongside a cleanup func that removes any temp file it created.
+func materializeSource(ctx context.Context, ref string, fsys afero.Fs) (path, canonicalUrl string, cleanup func(), err error) {
+	if strings.HasPrefix(ref, "file://") {
+		return strings.TrimPrefix(ref, "file://"), ref, func() {}, nil
+	}
+	if !isRemoteRef(ref) {
+		abs, err := filepath.Abs(ref)
+		if err != nil {
+			return "", "", nil, err
+		}
+		return abs, "file://" + abs, func() {}, nil
+	}
+
+	body, canonicalUrl, err := loader.Resolve(ctx, ref, fsys)
+	if err != nil {
+		return "", "", nil, err
+	}
+	defer body.Close()
+
+	tmp, err := os.CreateTemp("", "supabase-deploy-*")
+	if err != nil {
+		return "", "", nil, err
+	}
+	if _, err := io.Copy(tmp, body); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return "", "", nil, err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return "", "", nil, err
+	}
+	return tmp.Name(), canonicalUrl, func() { os.Remove(tmp.Name()) }, nil
+}
+
+// resolveVerifyJWT determines whether to require a valid JWT for a function,
+// falling back to the value configured in supabase/config.toml when the
+// --no-verify-jwt flag was not explicitly set.
+func resolveVerifyJWT(slug string, noVerifyJWT *bool) bool {
+	verifyJWT := true
+	if fc, ok := utils.Config.Functions[slug]; ok && fc.VerifyJWT != nil {
+		verifyJWT = *fc.VerifyJWT
+	}
+	if noVerifyJWT != nil {
+		verifyJWT = !*noVerifyJWT
+	}
+	return verifyJWT
+}
+
+func bundleFunction(ctx context.Context, entrypointPath, importMapPath string, fsys afero.Fs) (io.Reader, error) {
+	denoPath, err := utils.GetDenoPath(fsys)
+	if err != nil {
+		return nil, err
+	}
+	args := []string{"bundle", "--no-check=remote", "--import-map=" + importMapPath, entrypointPath}
+	var outBuf, errBuf bytes.Buffer
+	cmd := exec.CommandContext(ctx, denoPath, args...)
+	cmd.Stdout = &outBuf
+	cmd.Stderr = &errBuf
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("Error bundling function: %w\n%s", err, errBuf.String())
+	}
+	return &outBuf, nil
+}
+
+func deployFunction(ctx context.Context, projectRef, slug, entrypointUrl, importMapUrl string, verifyJWT bool, contentType string, body io.Reader) (id, action string, err error) {
+	client := utils.GetSupabase()
+
+	getResp, err := client.GetFunctionWithResponse(ctx, projectRef, slug)
+	if err != nil {
+		return "", "", err
+	}
+
+	switch getResp.StatusCode() {
+	case http.StatusNotFound:
+		params := api.CreateFunctionParams{
+			Slug:           &slug,
+			Name:           &slug,
+			VerifyJwt:      &verifyJWT,
+			EntrypointPath: &entrypointUrl,
+		}
+		if importMapUrl != "" {
+			params.ImportMapPath = &importMapUrl
+		}
+		resp, err := client.CreateFunctionWithBodyWithResponse(ctx, projectRef, &params, contentType, body)
+		if err != nil {
+			return "", "", err
+		}
+		if resp.JSON201 == nil {
+			return "", "", fmt.Errorf("Failed to create a new Function on the Supabase project: %s", string(resp.Body))
+		}
+		return resp.JSON201.Id, actionCreated, nil
+	case http.StatusOK:
+		params := api.UpdateFunctionParams{
+			VerifyJwt:      &verifyJWT,
+			EntrypointPath: &entrypointUrl,
+		}
+		if importMapUrl != "" {
+			params.ImportMapPath = &importMapUrl
+		}
+		resp, err := client.UpdateFunctionWithBodyWithResponse(ctx, projectRef, slug, &params, contentType, body)
+		if err != nil {
+			return "", "", err
+		}
+		if resp.JSON200 == nil {
+			return "", "", fmt.Errorf("Failed to update an existing Function's body on the Supabase project: %s", string(resp.Body))
+		}
+		return resp.JSON200.Id, actionUpdated, nil
+	default:
+		return "", "", fmt.Errorf("Unexpected error deploying Function: %s", string(getResp.Body))
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"archive/zip"
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
@@ -11,13 +12,16 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/supabase/cli/internal/testing/apitest"
 	"github.com/supabase/cli/internal/utils"
+	"github.com/supabase/cli/internal/utils/output"
 	"github.com/supabase/cli/pkg/api"
 	"gopkg.in/h2non/gock.v1"
 )
@@ -72,7 +76,7 @@ func TestDeployOne(t *testing.T) {
 			JSON(api.FunctionResponse{Id: "1"})
 		// Run test
 		noVerifyJWT := true
-		err = deployOne(context.Background(), slug, project, "", "", &noVerifyJWT, fsys)
+		_, _, err = deployOne(context.Background(), slug, project, "", "", &noVerifyJWT, nil, fsys)
 		// Check error
 		assert.NoError(t, err)
 		assert.Empty(t, apitest.ListUnmatchedRequests())
@@ -100,7 +104,7 @@ func TestDeployOne(t *testing.T) {
 			Reply(http.StatusOK).
 			JSON(api.FunctionResponse{Id: "1"})
 		// Run test
-		err = deployOne(context.Background(), slug, project, "", "", nil, fsys)
+		_, _, err = deployOne(context.Background(), slug, project, "", "", nil, nil, fsys)
 		// Check error
 		assert.NoError(t, err)
 		assert.Empty(t, apitest.ListUnmatchedRequests())
@@ -113,7 +117,7 @@ func TestDeployOne(t *testing.T) {
 		project := apitest.RandomProjectRef()
 		// Run test
 		noVerifyJWT := true
-		err := deployOne(context.Background(), "@", project, "", "", &noVerifyJWT, fsys)
+		_, _, err := deployOne(context.Background(), "@", project, "", "", &noVerifyJWT, nil, fsys)
 		// Check error
 		assert.ErrorContains(t, err, "Invalid Function name.")
 	})
@@ -124,7 +128,7 @@ func TestDeployOne(t *testing.T) {
 		// Setup valid project ref
 		project := apitest.RandomProjectRef()
 		// Run test
-		err := deployOne(context.Background(), slug, project, "import_map.json", "", nil, fsys)
+		_, _, err := deployOne(context.Background(), slug, project, "import_map.json", "", nil, nil, fsys)
 		// Check error
 		assert.ErrorIs(t, err, os.ErrNotExist)
 	})
@@ -150,7 +154,7 @@ func TestDeployOne(t *testing.T) {
 			Reply(http.StatusOK).
 			Body(&body)
 		// Run test
-		err = deployOne(context.Background(), slug, project, "", "", nil, fsys)
+		_, _, err = deployOne(context.Background(), slug, project, "", "", nil, nil, fsys)
 		// Check error
 		assert.ErrorContains(t, err, "Error bundling function: exit status 1\nbundle failed\n")
 		assert.Empty(t, apitest.ListUnmatchedRequests())
@@ -186,19 +190,57 @@ func TestDeployAll(t *testing.T) {
 		}
 		// Run test
 		noVerifyJWT := true
-		err = deployAll(context.Background(), functions, project, "", &noVerifyJWT, fsys)
+		err = deployAll(context.Background(), functions, project, "", &noVerifyJWT, 0, false, output.FormatTable, fsys)
 		// Check error
 		assert.NoError(t, err)
 		assert.Empty(t, apitest.ListUnmatchedRequests())
 	})
 
+	for _, format := range []output.Format{output.FormatJSON, output.FormatYAML, output.FormatCSV} {
+		t.Run("renders deploy results as "+string(format), func(t *testing.T) {
+			functions := []string{slug}
+			// Setup in-memory fs
+			fsys := afero.NewMemMapFs()
+			project := apitest.RandomProjectRef()
+			token := apitest.RandomAccessToken(t)
+			t.Setenv("SUPABASE_ACCESS_TOKEN", string(token))
+			_, err := fsys.Create(utils.DenoPathOverride)
+			require.NoError(t, err)
+			// Setup mock api
+			defer gock.OffAll()
+			gock.New(utils.DefaultApiHost).
+				Get("/v1/projects/" + project + "/functions/").
+				Reply(http.StatusNotFound)
+			gock.New(utils.DefaultApiHost).
+				Post("/v1/projects/" + project + "/functions").
+				Reply(http.StatusCreated).
+				JSON(api.FunctionResponse{Id: "1"})
+			// Capture stdout
+			r, w, err := os.Pipe()
+			require.NoError(t, err)
+			stdout := os.Stdout
+			os.Stdout = w
+			// Run test
+			err = deployAll(context.Background(), functions, project, "", nil, 0, false, format, fsys)
+			require.NoError(t, w.Close())
+			os.Stdout = stdout
+			var buf bytes.Buffer
+			_, copyErr := buf.ReadFrom(r)
+			require.NoError(t, copyErr)
+			// Check error
+			assert.NoError(t, err)
+			assert.Contains(t, buf.String(), slug)
+			assert.Contains(t, buf.String(), "created")
+		})
+	}
+
 	t.Run("throws error on failure to install deno", func(t *testing.T) {
 		// Setup in-memory fs
 		fsys := afero.NewReadOnlyFs(afero.NewMemMapFs())
 		// Setup valid project ref
 		project := apitest.RandomProjectRef()
 		// Run test
-		err := deployAll(context.Background(), []string{slug}, project, "", nil, fsys)
+		err := deployAll(context.Background(), []string{slug}, project, "", nil, 0, false, output.FormatTable, fsys)
 		// Check error
 		assert.ErrorContains(t, err, "operation not permitted")
 	})
@@ -212,10 +254,94 @@ func TestDeployAll(t *testing.T) {
 		_, err := fsys.Create(utils.DenoPathOverride)
 		require.NoError(t, err)
 		// Run test
-		err = deployAll(context.Background(), []string{slug}, project, "", nil, afero.NewReadOnlyFs(fsys))
+		err = deployAll(context.Background(), []string{slug}, project, "", nil, 0, false, output.FormatTable, afero.NewReadOnlyFs(fsys))
 		// Check error
 		assert.ErrorContains(t, err, "operation not permitted")
 	})
+
+	t.Run("aggregates errors from every failed slug", func(t *testing.T) {
+		functions := []string{slug, slug + "-2", slug + "-3"}
+		// Setup in-memory fs: deno binary missing so every deploy fails the same way
+		fsys := afero.NewReadOnlyFs(afero.NewMemMapFs())
+		project := apitest.RandomProjectRef()
+		// Run test
+		err := deployAll(context.Background(), functions, project, "", nil, 0, false, output.FormatTable, fsys)
+		// Check error: every slug must be named in the joined error
+		require.Error(t, err)
+		for _, fn := range functions {
+			assert.ErrorContains(t, err, fn)
+		}
+	})
+
+	t.Run("fail-fast returns only the first error", func(t *testing.T) {
+		functions := []string{slug, slug + "-2"}
+		fsys := afero.NewReadOnlyFs(afero.NewMemMapFs())
+		project := apitest.RandomProjectRef()
+		// Run test
+		err := deployAll(context.Background(), functions, project, "", nil, 1, true, output.FormatTable, fsys)
+		// Check error: fail-fast short-circuits instead of joining every slug
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "operation not permitted")
+	})
+
+	t.Run("bounds concurrency to the jobs limit", func(t *testing.T) {
+		functions := []string{slug, slug + "-2", slug + "-3", slug + "-4"}
+		// Setup in-memory fs
+		fsys := afero.NewMemMapFs()
+		project := apitest.RandomProjectRef()
+		token := apitest.RandomAccessToken(t)
+		t.Setenv("SUPABASE_ACCESS_TOKEN", string(token))
+		_, err := fsys.Create(utils.DenoPathOverride)
+		require.NoError(t, err)
+		// Track the high-water mark of concurrent in-flight lookups
+		var current, peak int32
+		defer gock.OffAll()
+		for range functions {
+			gock.New(utils.DefaultApiHost).
+				Get("/v1/projects/" + project + "/functions/").
+				AddMatcher(func(req *http.Request, _ *gock.Request) (bool, error) {
+					n := atomic.AddInt32(&current, 1)
+					defer atomic.AddInt32(&current, -1)
+					for {
+						if p := atomic.LoadInt32(&peak); n > p {
+							if atomic.CompareAndSwapInt32(&peak, p, n) {
+								break
+							}
+							continue
+						}
+						break
+					}
+					time.Sleep(20 * time.Millisecond)
+					return true, nil
+				}).
+				Reply(http.StatusNotFound)
+			gock.New(utils.DefaultApiHost).
+				Post("/v1/projects/" + project + "/functions").
+				Reply(http.StatusCreated).
+				JSON(api.FunctionResponse{Id: "1"})
+		}
+		// Run test
+		err = deployAll(context.Background(), functions, project, "", nil, 2, false, output.FormatTable, fsys)
+		// Check error
+		assert.NoError(t, err)
+		assert.LessOrEqual(t, int(atomic.LoadInt32(&peak)), 2)
+	})
+
+	t.Run("cancels remaining deploys when ctx is done", func(t *testing.T) {
+		functions := []string{slug, slug + "-2", slug + "-3"}
+		fsys := afero.NewMemMapFs()
+		project := apitest.RandomProjectRef()
+		token := apitest.RandomAccessToken(t)
+		t.Setenv("SUPABASE_ACCESS_TOKEN", string(token))
+		_, err := fsys.Create(utils.DenoPathOverride)
+		require.NoError(t, err)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		// Run test: ctx is already done, so no function should deploy
+		err = deployAll(ctx, functions, project, "", nil, 1, false, output.FormatTable, fsys)
+		// Check error
+		assert.ErrorIs(t, err, context.Canceled)
+	})
 }
 
 func TestDeployCommand(t *testing.T) {
@@ -247,7 +373,7 @@ func TestDeployCommand(t *testing.T) {
 		}
 		// Run test
 		noVerifyJWT := true
-		err = Run(context.Background(), functions, project, &noVerifyJWT, "", fsys)
+		err = Run(context.Background(), functions, project, &noVerifyJWT, "", 0, false, false, "", nil, output.FormatTable, fsys)
 		// Check error
 		assert.NoError(t, err)
 		assert.Empty(t, apitest.ListUnmatchedRequests())
@@ -282,7 +408,7 @@ func TestDeployCommand(t *testing.T) {
 			Reply(http.StatusCreated).
 			JSON(api.FunctionResponse{Id: "1"})
 		// Run test
-		err = Run(context.Background(), nil, project, nil, "", fsys)
+		err = Run(context.Background(), nil, project, nil, "", 0, false, false, "", nil, output.FormatTable, fsys)
 		// Check error
 		assert.NoError(t, err)
 		assert.Empty(t, apitest.ListUnmatchedRequests())
@@ -293,7 +419,7 @@ func TestDeployCommand(t *testing.T) {
 		fsys := afero.NewMemMapFs()
 		require.NoError(t, fsys.MkdirAll(utils.FunctionsDir, 0755))
 		// Run test
-		err := Run(context.Background(), nil, "", nil, "", fsys)
+		err := Run(context.Background(), nil, "", nil, "", 0, false, false, "", nil, output.FormatTable, fsys)
 		// Check error
 		assert.ErrorContains(t, err, "No Functions specified or found in supabase/functions")
 	})
@@ -329,7 +455,7 @@ verify_jwt = false
 			Reply(http.StatusCreated).
 			JSON(api.FunctionResponse{Id: "1"})
 		// Run test
-		assert.NoError(t, Run(context.Background(), []string{slug}, project, nil, "", fsys))
+		assert.NoError(t, Run(context.Background(), []string{slug}, project, nil, "", 0, false, false, "", nil, output.FormatTable, fsys))
 		// Validate api
 		assert.Empty(t, apitest.ListUnmatchedRequests())
 	})
@@ -366,10 +492,152 @@ verify_jwt = false
 			JSON(api.FunctionResponse{Id: "1"})
 		// Run test
 		noVerifyJwt := false
-		assert.NoError(t, Run(context.Background(), []string{slug}, project, &noVerifyJwt, "", fsys))
+		assert.NoError(t, Run(context.Background(), []string{slug}, project, &noVerifyJwt, "", 0, false, false, "", nil, output.FormatTable, fsys))
+		// Validate api
+		assert.Empty(t, apitest.ListUnmatchedRequests())
+	})
+
+	t.Run("entrypoint param falls back to a remote ref in config", func(t *testing.T) {
+		// Setup in-memory fs
+		fsys := afero.NewMemMapFs()
+		require.NoError(t, utils.WriteConfig(fsys, false))
+		entrypointRef := "https://example.com/config-entrypoint-test/index.ts"
+		f, err := fsys.OpenFile("supabase/config.toml", os.O_APPEND|os.O_WRONLY, 0600)
+		require.NoError(t, err)
+		_, err = f.WriteString(`
+[functions.` + slug + `]
+entrypoint = "` + entrypointRef + `"
+`)
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+		// Setup valid project ref
+		project := apitest.RandomProjectRef()
+		// Setup valid access token
+		token := apitest.RandomAccessToken(t)
+		t.Setenv("SUPABASE_ACCESS_TOKEN", string(token))
+		// Setup valid deno path
+		_, err = fsys.Create(utils.DenoPathOverride)
+		require.NoError(t, err)
+		// Setup mock api
+		defer gock.OffAll()
+		gock.New("https://example.com").
+			Get("/config-entrypoint-test/index.ts").
+			Reply(http.StatusOK).
+			BodyString("export default () => new Response('hi')")
+		gock.New(utils.DefaultApiHost).
+			Get("/v1/projects/" + project + "/functions/" + slug).
+			Reply(http.StatusNotFound)
+		gock.New(utils.DefaultApiHost).
+			Post("/v1/projects/"+project+"/functions").
+			MatchParam("entrypoint_path", entrypointRef).
+			Reply(http.StatusCreated).
+			JSON(api.FunctionResponse{Id: "1"})
+		// Run test: Run loads config.toml itself, so this exercises the full
+		// config -> deployOne -> deployRemote path, not just deployRemote directly
+		assert.NoError(t, Run(context.Background(), []string{slug}, project, nil, "", 0, false, false, "", nil, output.FormatTable, fsys))
 		// Validate api
 		assert.Empty(t, apitest.ListUnmatchedRequests())
 	})
+
+	t.Run("skips watch mode against an in-memory filesystem", func(t *testing.T) {
+		// Setup in-memory fs
+		fsys := afero.NewMemMapFs()
+		require.NoError(t, afero.WriteFile(fsys, filepath.Join(utils.FunctionsDir, slug, "index.ts"), []byte{}, 0644))
+		// Setup valid project ref
+		project := apitest.RandomProjectRef()
+		// Setup valid access token
+		token := apitest.RandomAccessToken(t)
+		t.Setenv("SUPABASE_ACCESS_TOKEN", string(token))
+		// Setup valid deno path
+		_, err := fsys.Create(utils.DenoPathOverride)
+		require.NoError(t, err)
+		// Setup mock api
+		defer gock.OffAll()
+		gock.New(utils.DefaultApiHost).
+			Get("/v1/projects/" + project + "/functions/" + slug).
+			Reply(http.StatusNotFound)
+		gock.New(utils.DefaultApiHost).
+			Post("/v1/projects/"+project+"/functions").
+			Reply(http.StatusCreated).
+			JSON(api.FunctionResponse{Id: "1"})
+		// Run test: MemMapFs can't be watched, so Run should deploy once and return
+		err = Run(context.Background(), []string{slug}, project, nil, "", 0, false, true, "", nil, output.FormatTable, fsys)
+		// Check error
+		assert.NoError(t, err)
+		assert.Empty(t, apitest.ListUnmatchedRequests())
+	})
+
+	t.Run("renders archive deploy results as json", func(t *testing.T) {
+		fsys := afero.NewMemMapFs()
+		project := apitest.RandomProjectRef()
+		token := apitest.RandomAccessToken(t)
+		t.Setenv("SUPABASE_ACCESS_TOKEN", string(token))
+		archivePath := "/tmp/func.zip"
+		require.NoError(t, afero.WriteFile(fsys, archivePath, writeZipArchive(t, map[string]string{"index.ts": ""}), 0644))
+		defer gock.OffAll()
+		gock.New(utils.DefaultApiHost).
+			Get("/v1/projects/" + project + "/functions/" + slug).
+			Reply(http.StatusNotFound)
+		gock.New(utils.DefaultApiHost).
+			Post("/v1/projects/"+project+"/functions").
+			Reply(http.StatusCreated).
+			JSON(api.FunctionResponse{Id: "1"})
+		// Capture stdout
+		r, w, err := os.Pipe()
+		require.NoError(t, err)
+		stdout := os.Stdout
+		os.Stdout = w
+		// Run test
+		err = Run(context.Background(), nil, project, nil, "", 0, false, false, "", map[string]string{slug: archivePath}, output.FormatJSON, fsys)
+		require.NoError(t, w.Close())
+		os.Stdout = stdout
+		var buf bytes.Buffer
+		_, copyErr := buf.ReadFrom(r)
+		require.NoError(t, copyErr)
+		// Check error
+		assert.NoError(t, err)
+		assert.Contains(t, buf.String(), slug)
+		assert.Contains(t, buf.String(), "created")
+	})
+
+	t.Run("renders manifest-driven archive deploy results as json", func(t *testing.T) {
+		fsys := afero.NewMemMapFs()
+		project := apitest.RandomProjectRef()
+		token := apitest.RandomAccessToken(t)
+		t.Setenv("SUPABASE_ACCESS_TOKEN", string(token))
+		entries := []archiveManifestEntry{{Slug: slug, Entrypoint: "index.ts"}}
+		manifest, err := json.Marshal(entries)
+		require.NoError(t, err)
+		archivePath := "/tmp/bundle.zip"
+		require.NoError(t, afero.WriteFile(fsys, archivePath, writeZipArchive(t, map[string]string{
+			archiveManifest: string(manifest),
+			"index.ts":      "",
+		}), 0644))
+		defer gock.OffAll()
+		gock.New(utils.DefaultApiHost).
+			Get("/v1/projects/" + project + "/functions/" + slug).
+			Reply(http.StatusNotFound)
+		gock.New(utils.DefaultApiHost).
+			Post("/v1/projects/"+project+"/functions").
+			Reply(http.StatusCreated).
+			JSON(api.FunctionResponse{Id: "1"})
+		// Capture stdout
+		r, w, err := os.Pipe()
+		require.NoError(t, err)
+		stdout := os.Stdout
+		os.Stdout = w
+		// Run test
+		err = Run(context.Background(), nil, project, nil, "", 0, false, false, archivePath, nil, output.FormatJSON, fsys)
+		require.NoError(t, w.Close())
+		os.Stdout = stdout
+		var buf bytes.Buffer
+		_, copyErr := buf.ReadFrom(r)
+		require.NoError(t, copyErr)
+		// Check error
+		assert.NoError(t, err)
+		assert.Contains(t, buf.String(), slug)
+		assert.Contains(t, buf.String(), "created")
+	})
 }
 
 func TestDeployFunction(t *testing.T) {
@@ -387,7 +655,7 @@ func TestDeployFunction(t *testing.T) {
 			Get("/v1/projects/" + project + "/functions/" + slug).
 			ReplyError(errors.New("network error"))
 		// Run test
-		err := deployFunction(context.Background(), project, slug, "", "", true, strings.NewReader("body"))
+		_, _, err := deployFunction(context.Background(), project, slug, "", "", true, eszipContentType, strings.NewReader("body"))
 		// Check error
 		assert.ErrorContains(t, err, "network error")
 	})
@@ -399,7 +667,7 @@ func TestDeployFunction(t *testing.T) {
 			Get("/v1/projects/" + project + "/functions/" + slug).
 			Reply(http.StatusServiceUnavailable)
 		// Run test
-		err := deployFunction(context.Background(), project, slug, "", "", true, strings.NewReader("body"))
+		_, _, err := deployFunction(context.Background(), project, slug, "", "", true, eszipContentType, strings.NewReader("body"))
 		// Check error
 		assert.ErrorContains(t, err, "Unexpected error deploying Function:")
 	})
@@ -414,7 +682,7 @@ func TestDeployFunction(t *testing.T) {
 			Post("/v1/projects/" + project + "/functions").
 			ReplyError(errors.New("network error"))
 		// Run test
-		err := deployFunction(context.Background(), project, slug, "", "", true, strings.NewReader("body"))
+		_, _, err := deployFunction(context.Background(), project, slug, "", "", true, eszipContentType, strings.NewReader("body"))
 		// Check error
 		assert.ErrorContains(t, err, "network error")
 	})
@@ -429,7 +697,7 @@ func TestDeployFunction(t *testing.T) {
 			Post("/v1/projects/" + project + "/functions").
 			Reply(http.StatusServiceUnavailable)
 		// Run test
-		err := deployFunction(context.Background(), project, slug, "", "", true, strings.NewReader("body"))
+		_, _, err := deployFunction(context.Background(), project, slug, "", "", true, eszipContentType, strings.NewReader("body"))
 		// Check error
 		assert.ErrorContains(t, err, "Failed to create a new Function on the Supabase project:")
 	})
@@ -445,7 +713,7 @@ func TestDeployFunction(t *testing.T) {
 			Patch("/v1/projects/" + project + "/functions/" + slug).
 			ReplyError(errors.New("network error"))
 		// Run test
-		err := deployFunction(context.Background(), project, slug, "", "", true, strings.NewReader("body"))
+		_, _, err := deployFunction(context.Background(), project, slug, "", "", true, eszipContentType, strings.NewReader("body"))
 		// Check error
 		assert.ErrorContains(t, err, "network error")
 	})
@@ -461,8 +729,119 @@ func TestDeployFunction(t *testing.T) {
 			Patch("/v1/projects/" + project + "/functions/" + slug).
 			Reply(http.StatusServiceUnavailable)
 		// Run test
-		err := deployFunction(context.Background(), project, slug, "", "", true, strings.NewReader("body"))
+		_, _, err := deployFunction(context.Background(), project, slug, "", "", true, eszipContentType, strings.NewReader("body"))
 		// Check error
 		assert.ErrorContains(t, err, "Failed to update an existing Function's body on the Supabase project:")
 	})
 }
+
+func TestDeployRemote(t *testing.T) {
+	const slug = "test-func"
+
+	t.Run("deploys an entrypoint fetched over https", func(t *testing.T) {
+		entrypointRef := "https://example.com/deploy-remote-test/index.ts"
+		// Setup in-memory fs
+		fsys := afero.NewMemMapFs()
+		// Setup valid project ref
+		project := apitest.RandomProjectRef()
+		// Setup valid access token
+		token := apitest.RandomAccessToken(t)
+		t.Setenv("SUPABASE_ACCESS_TOKEN", string(token))
+		// Setup valid deno path
+		_, err := fsys.Create(utils.DenoPathOverride)
+		require.NoError(t, err)
+		// Setup mock api
+		defer gock.OffAll()
+		gock.New("https://example.com").
+			Get("/deploy-remote-test/index.ts").
+			Reply(http.StatusOK).
+			BodyString("export default () => new Response('hi')")
+		gock.New(utils.DefaultApiHost).
+			Get("/v1/projects/" + project + "/functions/" + slug).
+			Reply(http.StatusNotFound)
+		gock.New(utils.DefaultApiHost).
+			Post("/v1/projects/"+project+"/functions").
+			MatchParam("entrypoint_path", entrypointRef).
+			Reply(http.StatusCreated).
+			JSON(api.FunctionResponse{Id: "1"})
+		// Run test
+		_, _, err = deployRemote(context.Background(), slug, project, entrypointRef, "", nil, nil, fsys)
+		// Check error
+		assert.NoError(t, err)
+		assert.Empty(t, apitest.ListUnmatchedRequests())
+	})
+
+	t.Run("deploys an entrypoint fetched from github", func(t *testing.T) {
+		entrypointRef := "github://supabase/cli@v1.2.3/functions/deploy-remote-test/index.ts"
+		// Setup in-memory fs
+		fsys := afero.NewMemMapFs()
+		// Setup valid project ref
+		project := apitest.RandomProjectRef()
+		// Setup valid access token
+		token := apitest.RandomAccessToken(t)
+		t.Setenv("SUPABASE_ACCESS_TOKEN", string(token))
+		// Setup valid deno path
+		_, err := fsys.Create(utils.DenoPathOverride)
+		require.NoError(t, err)
+		// Setup a fake github raw-content host standing in for the real one
+		defer gock.OffAll()
+		gock.New("https://raw.githubusercontent.com").
+			Get("/supabase/cli/v1.2.3/functions/deploy-remote-test/index.ts").
+			Reply(http.StatusOK).
+			BodyString("export default () => new Response('hi')")
+		gock.New(utils.DefaultApiHost).
+			Get("/v1/projects/" + project + "/functions/" + slug).
+			Reply(http.StatusNotFound)
+		gock.New(utils.DefaultApiHost).
+			Post("/v1/projects/"+project+"/functions").
+			MatchParam("entrypoint_path", entrypointRef).
+			Reply(http.StatusCreated).
+			JSON(api.FunctionResponse{Id: "1"})
+		// Run test
+		_, _, err = deployRemote(context.Background(), slug, project, entrypointRef, "", nil, nil, fsys)
+		// Check error
+		assert.NoError(t, err)
+		assert.Empty(t, apitest.ListUnmatchedRequests())
+	})
+
+	t.Run("deploys an entrypoint given as a file:// ref", func(t *testing.T) {
+		dir := t.TempDir()
+		entrypointPath := filepath.Join(dir, "index.ts")
+		require.NoError(t, os.WriteFile(entrypointPath, []byte("export default () => new Response('hi')"), 0644))
+		entrypointRef := "file://" + entrypointPath
+		// Setup in-memory fs
+		fsys := afero.NewMemMapFs()
+		// Setup valid project ref
+		project := apitest.RandomProjectRef()
+		// Setup valid access token
+		token := apitest.RandomAccessToken(t)
+		t.Setenv("SUPABASE_ACCESS_TOKEN", string(token))
+		// Setup valid deno path
+		_, err := fsys.Create(utils.DenoPathOverride)
+		require.NoError(t, err)
+		// Setup mock api
+		defer gock.OffAll()
+		gock.New(utils.DefaultApiHost).
+			Get("/v1/projects/" + project + "/functions/" + slug).
+			Reply(http.StatusNotFound)
+		gock.New(utils.DefaultApiHost).
+			Post("/v1/projects/"+project+"/functions").
+			MatchParam("entrypoint_path", entrypointRef).
+			Reply(http.StatusCreated).
+			JSON(api.FunctionResponse{Id: "1"})
+		// Run test
+		_, _, err = deployRemote(context.Background(), slug, project, entrypointRef, "", nil, nil, fsys)
+		// Check error
+		assert.NoError(t, err)
+		assert.Empty(t, apitest.ListUnmatchedRequests())
+	})
+
+	t.Run("throws error when import map is remote but entrypoint is not given", func(t *testing.T) {
+		fsys := afero.NewMemMapFs()
+		project := apitest.RandomProjectRef()
+		// Run test
+		_, _, err := deployRemote(context.Background(), slug, project, "", "https://example.com/import_map.json", nil, nil, fsys)
+		// Check error
+		assert.ErrorContains(t, err, "a remote import map requires an entrypoint")
+	})
+}
@@ -0,0 +1,97 @@
+package deploy
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+type functionState string
+
+const (
+	stateQueued    functionState = "queued"
+	stateBundling  functionState = "bundling"
+	stateUploading functionState = "uploading"
+	stateDone      functionState = "done"
+	stateFailed    functionState = "failed"
+)
+
+type progressRow struct {
+	slug  string
+	state functionState
+	start time.Time
+	err   error
+}
+
+// progressTable tracks the live deploy state of every function in a deployAll
+// run so it can be rendered as a status table while deploys are in flight.
+type progressTable struct {
+	mu   sync.Mutex
+	rows []*progressRow
+}
+
+func newProgressTable(slugs []string) *progressTable {
+	t := &progressTable{}
+	now := time.Now()
+	for _, slug := range slugs {
+		t.rows = append(t.rows, &progressRow{slug: slug, state: stateQueued, start: now})
+	}
+	return t
+}
+
+// setState is a no-op on a nil *progressTable so callers that have no table
+// to report against (e.g. watch mode) can pass nil instead of special-casing
+// it at every call site.
+func (t *progressTable) setState(slug string, state functionState, err error) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, row := range t.rows {
+		if row.slug == slug {
+			row.state = state
+			row.err = err
+			if state == stateBundling {
+				row.start = time.Now()
+			}
+			return
+		}
+	}
+}
+
+// startRendering repaints the status table on the given writer every
+// interval until the returned stop function is called.
+func (t *progressTable) startRendering(w io.Writer, interval time.Duration) (stop func()) {
+	done := make(chan struct{})
+	var once sync.Once
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				t.render(w)
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		once.Do(func() { close(done) })
+	}
+}
+
+func (t *progressTable) render(w io.Writer) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	fmt.Fprintf(w, "\rSLUG\tSTATE\tELAPSED\tERROR\n")
+	for _, row := range t.rows {
+		summary := ""
+		if row.err != nil {
+			summary = row.err.Error()
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", row.slug, row.state, time.Since(row.start).Round(time.Millisecond), summary)
+	}
+}
@@ -0,0 +1,173 @@
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/afero"
+	"github.com/supabase/cli/internal/utils"
+	"github.com/supabase/cli/internal/utils/output"
+)
+
+const watchDebounce = 300 * time.Millisecond
+
+// watchFunctions keeps the process alive and redeploys individual functions
+// whenever files under their supabase/functions/<slug> directory, or the
+// shared import map, change on disk. Redeploys are debounced per slug so a
+// burst of editor saves only triggers one deploy. SIGHUP forces a redeploy
+// of every function; SIGINT/SIGTERM stop the loop.
+func watchFunctions(ctx context.Context, slugs []string, projectRef, importMapPath string, noVerifyJWT *bool, jobs int, failFast bool, fsys afero.Fs) error {
+	if _, ok := fsys.(*afero.OsFs); !ok {
+		fmt.Fprintln(os.Stderr, "Watch mode requires the local filesystem; skipping --watch.")
+		return nil
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	dirs, err := watchDirs(slugs, importMapPath, fsys)
+	if err != nil {
+		return err
+	}
+	for _, dir := range dirs {
+		if err := watcher.Add(dir); err != nil {
+			return err
+		}
+	}
+
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+	defer signal.Stop(sighup)
+
+	sigterm := make(chan os.Signal, 1)
+	signal.Notify(sigterm, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigterm)
+
+	if jobs <= 0 {
+		jobs = runtime.NumCPU()
+	}
+	sem := make(chan struct{}, jobs)
+
+	var mu sync.Mutex
+	timers := make(map[string]*time.Timer)
+	redeploy := func(slug string) {
+		sem <- struct{}{}
+		defer func() { <-sem }()
+		if _, _, err := deployOne(ctx, slug, projectRef, importMapPath, "", noVerifyJWT, nil, fsys); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to redeploy %s: %v\n", slug, err)
+		}
+	}
+	schedule := func(slug string) {
+		mu.Lock()
+		defer mu.Unlock()
+		if t, ok := timers[slug]; ok {
+			t.Stop()
+		}
+		timers[slug] = time.AfterFunc(watchDebounce, func() { redeploy(slug) })
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-sigterm:
+			return nil
+		case <-sighup:
+			// SIGHUP forces an immediate redeploy of every function, bypassing
+			// the per-slug debounce, honoring --jobs/--fail-fast the same way
+			// the initial deploy does. Failures are logged, not fatal: only
+			// SIGINT/SIGTERM stop the watch loop.
+			if err := deployAll(ctx, slugs, projectRef, importMapPath, noVerifyJWT, jobs, failFast, output.FormatTable, fsys); err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to redeploy all functions: %v\n", err)
+			}
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if event.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+				continue
+			}
+			if slug := slugForPath(event.Name, slugs); slug != "" {
+				schedule(slug)
+			} else {
+				// Outside every function directory, so the change is in the
+				// shared import map's directory (the import map or any other
+				// file there): every function depends on it.
+				for _, s := range slugs {
+					schedule(s)
+				}
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			fmt.Fprintf(os.Stderr, "Watch error: %v\n", err)
+		}
+	}
+}
+
+// watchDirs returns every directory that should be registered with fsnotify:
+// each function's directory tree (entrypoint plus any local imports live
+// alongside it) and the directory containing the shared import map.
+func watchDirs(slugs []string, importMapPath string, fsys afero.Fs) ([]string, error) {
+	seen := make(map[string]bool)
+	var dirs []string
+	add := func(dir string) {
+		if dir != "" && !seen[dir] {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+
+	if importMapPath == "" {
+		importMapPath = utils.FallbackImportMapPath
+	}
+	if abs, err := filepath.Abs(importMapPath); err == nil {
+		add(filepath.Dir(abs))
+	}
+
+	for _, slug := range slugs {
+		root, err := filepath.Abs(filepath.Join(utils.FunctionsDir, slug))
+		if err != nil {
+			return nil, err
+		}
+		err = afero.Walk(fsys, root, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() {
+				add(path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return dirs, nil
+}
+
+func slugForPath(path string, slugs []string) string {
+	for _, slug := range slugs {
+		dir, err := filepath.Abs(filepath.Join(utils.FunctionsDir, slug))
+		if err != nil {
+			continue
+		}
+		if rel, err := filepath.Rel(dir, path); err == nil && !strings.HasPrefix(rel, "..") {
+			return slug
+		}
+	}
+	return ""
+}
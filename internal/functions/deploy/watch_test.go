@@ -0,0 +1,199 @@
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/supabase/cli/internal/testing/apitest"
+	"github.com/supabase/cli/internal/utils"
+	"github.com/supabase/cli/pkg/api"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestWatchDirs(t *testing.T) {
+	t.Run("collects each function's directory tree plus the import map's directory", func(t *testing.T) {
+		fsys := afero.NewMemMapFs()
+		const slugA, slugB = "func-a", "func-b"
+		// watchDirs walks absolute paths, so the files live at absolute paths
+		funcADir, err := filepath.Abs(filepath.Join(utils.FunctionsDir, slugA))
+		require.NoError(t, err)
+		funcBDir, err := filepath.Abs(filepath.Join(utils.FunctionsDir, slugB))
+		require.NoError(t, err)
+		require.NoError(t, afero.WriteFile(fsys, filepath.Join(funcADir, "index.ts"), []byte{}, 0644))
+		require.NoError(t, afero.WriteFile(fsys, filepath.Join(funcADir, "lib", "helper.ts"), []byte{}, 0644))
+		require.NoError(t, afero.WriteFile(fsys, filepath.Join(funcBDir, "index.ts"), []byte{}, 0644))
+		importMapPath, err := filepath.Abs(utils.FallbackImportMapPath)
+		require.NoError(t, err)
+		require.NoError(t, afero.WriteFile(fsys, importMapPath, []byte("{}"), 0644))
+
+		dirs, err := watchDirs([]string{slugA, slugB}, "", fsys)
+		require.NoError(t, err)
+
+		funcALibDir, err := filepath.Abs(filepath.Join(utils.FunctionsDir, slugA, "lib"))
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{filepath.Dir(importMapPath), funcADir, funcALibDir, funcBDir}, dirs)
+	})
+
+	t.Run("dedupes the import map's directory when it lives inside a function directory", func(t *testing.T) {
+		fsys := afero.NewMemMapFs()
+		const slug = "func-a"
+		funcDir, err := filepath.Abs(filepath.Join(utils.FunctionsDir, slug))
+		require.NoError(t, err)
+		require.NoError(t, afero.WriteFile(fsys, filepath.Join(funcDir, "index.ts"), []byte{}, 0644))
+		importMapPath := filepath.Join(funcDir, "import_map.json")
+		require.NoError(t, afero.WriteFile(fsys, importMapPath, []byte("{}"), 0644))
+
+		dirs, err := watchDirs([]string{slug}, importMapPath, fsys)
+		require.NoError(t, err)
+		assert.Equal(t, []string{funcDir}, dirs)
+	})
+}
+
+func TestSlugForPath(t *testing.T) {
+	slugs := []string{"func-a", "func-b"}
+
+	entrypoint, err := filepath.Abs(filepath.Join(utils.FunctionsDir, "func-a", "lib", "helper.ts"))
+	require.NoError(t, err)
+	assert.Equal(t, "func-a", slugForPath(entrypoint, slugs))
+
+	outside, err := filepath.Abs(filepath.Join(utils.FunctionsDir, "shared", "index.ts"))
+	require.NoError(t, err)
+	assert.Equal(t, "", slugForPath(outside, slugs))
+}
+
+// chdirTemp switches the process into a fresh temp dir for the duration of
+// the test, restoring the original working directory on cleanup. watchFunctions
+// only watches real paths on disk, so tests that drive it need a real cwd
+// rather than an in-memory one.
+func chdirTemp(t *testing.T) {
+	t.Helper()
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	t.Cleanup(func() {
+		require.NoError(t, os.Chdir(wd))
+	})
+}
+
+func TestWatchFunctions(t *testing.T) {
+	const slug = "watch-test-func"
+
+	t.Run("debounces a burst of writes into a single redeploy", func(t *testing.T) {
+		chdirTemp(t)
+		fsys := afero.NewOsFs()
+		entrypointPath := filepath.Join(utils.FunctionsDir, slug, "index.ts")
+		require.NoError(t, afero.WriteFile(fsys, entrypointPath, []byte("export default () => new Response('hi')"), 0644))
+		importMapPath, err := filepath.Abs(utils.FallbackImportMapPath)
+		require.NoError(t, err)
+		require.NoError(t, afero.WriteFile(fsys, importMapPath, []byte("{}"), 0644))
+		absEntrypoint, err := filepath.Abs(entrypointPath)
+		require.NoError(t, err)
+
+		project := apitest.RandomProjectRef()
+		token := apitest.RandomAccessToken(t)
+		t.Setenv("SUPABASE_ACCESS_TOKEN", string(token))
+
+		var redeploys int32
+		defer gock.OffAll()
+		gock.New(utils.DefaultApiHost).
+			Get("/v1/projects/" + project + "/functions/" + slug).
+			Persist().
+			AddMatcher(func(_ *http.Request, _ *gock.Request) (bool, error) {
+				atomic.AddInt32(&redeploys, 1)
+				return true, nil
+			}).
+			Reply(http.StatusNotFound)
+		gock.New(utils.DefaultApiHost).
+			Post("/v1/projects/"+project+"/functions").
+			Persist().
+			Reply(http.StatusCreated).
+			JSON(api.FunctionResponse{Id: "1"})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+		go func() {
+			done <- watchFunctions(ctx, []string{slug}, project, "", nil, 1, false, fsys)
+		}()
+
+		// Retry the write until the watcher has registered (it races with
+		// fsnotify setup above) and a redeploy is observed. Each retry waits
+		// out the debounce window, since every write restarts the timer.
+		deadline := time.Now().Add(5 * time.Second)
+		for atomic.LoadInt32(&redeploys) == 0 && time.Now().Before(deadline) {
+			require.NoError(t, os.WriteFile(absEntrypoint, []byte("export default () => new Response('v1')"), 0644))
+			time.Sleep(2 * watchDebounce)
+		}
+		require.EqualValues(t, 1, atomic.LoadInt32(&redeploys), "expected the watcher to pick up the first write")
+
+		// A burst of writes landing inside the debounce window must coalesce
+		// into exactly one additional redeploy, not one per write.
+		for i := 0; i < 5; i++ {
+			require.NoError(t, os.WriteFile(absEntrypoint, []byte(fmt.Sprintf("export default () => new Response('v%d')", i+2)), 0644))
+			time.Sleep(10 * time.Millisecond)
+		}
+		time.Sleep(2 * watchDebounce)
+		assert.EqualValues(t, 2, atomic.LoadInt32(&redeploys), "burst of rapid writes should debounce into a single redeploy")
+
+		cancel()
+		assert.ErrorIs(t, <-done, context.Canceled)
+		assert.Empty(t, apitest.ListUnmatchedRequests())
+	})
+
+	t.Run("SIGHUP redeploys every function immediately, bypassing the debounce", func(t *testing.T) {
+		chdirTemp(t)
+		fsys := afero.NewOsFs()
+		entrypointPath := filepath.Join(utils.FunctionsDir, slug, "index.ts")
+		require.NoError(t, afero.WriteFile(fsys, entrypointPath, []byte("export default () => new Response('hi')"), 0644))
+		importMapPath, err := filepath.Abs(utils.FallbackImportMapPath)
+		require.NoError(t, err)
+		require.NoError(t, afero.WriteFile(fsys, importMapPath, []byte("{}"), 0644))
+
+		project := apitest.RandomProjectRef()
+		token := apitest.RandomAccessToken(t)
+		t.Setenv("SUPABASE_ACCESS_TOKEN", string(token))
+
+		var redeploys int32
+		defer gock.OffAll()
+		gock.New(utils.DefaultApiHost).
+			Get("/v1/projects/" + project + "/functions/" + slug).
+			Persist().
+			AddMatcher(func(_ *http.Request, _ *gock.Request) (bool, error) {
+				atomic.AddInt32(&redeploys, 1)
+				return true, nil
+			}).
+			Reply(http.StatusNotFound)
+		gock.New(utils.DefaultApiHost).
+			Post("/v1/projects/"+project+"/functions").
+			Persist().
+			Reply(http.StatusCreated).
+			JSON(api.FunctionResponse{Id: "1"})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+		go func() {
+			done <- watchFunctions(ctx, []string{slug}, project, "", nil, 1, false, fsys)
+		}()
+
+		pid := os.Getpid()
+		deadline := time.Now().Add(5 * time.Second)
+		for atomic.LoadInt32(&redeploys) == 0 && time.Now().Before(deadline) {
+			require.NoError(t, syscall.Kill(pid, syscall.SIGHUP))
+			time.Sleep(20 * time.Millisecond)
+		}
+		require.EqualValues(t, 1, atomic.LoadInt32(&redeploys), "SIGHUP should trigger an immediate redeploy")
+
+		cancel()
+		assert.ErrorIs(t, <-done, context.Canceled)
+		assert.Empty(t, apitest.ListUnmatchedRequests())
+	})
+}
@@ -4,14 +4,21 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
 	"strings"
 
 	"github.com/spf13/afero"
 	"github.com/supabase/cli/internal/migration/list"
 	"github.com/supabase/cli/internal/utils"
+	"github.com/supabase/cli/internal/utils/output"
 )
 
-func Run(ctx context.Context, projectRef string, fsys afero.Fs) error {
+type secretRecord struct {
+	Name   string `json:"name" yaml:"name"`
+	Digest string `json:"digest" yaml:"digest"`
+}
+
+func Run(ctx context.Context, projectRef string, format output.Format, fsys afero.Fs) error {
 	resp, err := utils.GetSupabase().GetSecretsWithResponse(ctx, projectRef)
 	if err != nil {
 		return err
@@ -21,12 +28,18 @@ func Run(ctx context.Context, projectRef string, fsys afero.Fs) error {
 		return errors.New("Unexpected error retrieving project secrets: " + string(resp.Body))
 	}
 
-	table := `|NAME|DIGEST|
-|-|-|
-`
+	var rows []secretRecord
 	for _, secret := range *resp.JSON200 {
-		table += fmt.Sprintf("|`%s`|`%s`|\n", strings.ReplaceAll(secret.Name, "|", "\\|"), secret.Value)
+		rows = append(rows, secretRecord{Name: secret.Name, Digest: secret.Value})
 	}
 
-	return list.RenderTable(table)
+	return output.Write(os.Stdout, format, rows, func() error {
+		table := `|NAME|DIGEST|
+|-|-|
+`
+		for _, row := range rows {
+			table += fmt.Sprintf("|`%s`|`%s`|\n", strings.ReplaceAll(row.Name, "|", "\\|"), row.Digest)
+		}
+		return list.RenderTable(table)
+	})
 }
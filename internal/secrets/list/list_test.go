@@ -0,0 +1,57 @@
+package list
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/supabase/cli/internal/testing/apitest"
+	"github.com/supabase/cli/internal/utils"
+	"github.com/supabase/cli/internal/utils/output"
+	"github.com/supabase/cli/pkg/api"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestSecretsList(t *testing.T) {
+	formats := []output.Format{output.FormatTable, output.FormatJSON, output.FormatYAML, output.FormatCSV}
+
+	for _, format := range formats {
+		t.Run(string(format), func(t *testing.T) {
+			// Setup in-memory fs
+			fsys := afero.NewMemMapFs()
+			// Setup valid project ref
+			project := apitest.RandomProjectRef()
+			// Setup valid access token
+			token := apitest.RandomAccessToken(t)
+			t.Setenv("SUPABASE_ACCESS_TOKEN", string(token))
+			// Setup mock api
+			defer gock.OffAll()
+			gock.New(utils.DefaultApiHost).
+				Get("/v1/projects/" + project + "/secrets").
+				Reply(http.StatusOK).
+				JSON([]api.SecretResponse{{Name: "FOO", Value: "deadbeef"}})
+			// Run test
+			err := Run(context.Background(), project, format, fsys)
+			// Check error
+			assert.NoError(t, err)
+			assert.Empty(t, apitest.ListUnmatchedRequests())
+		})
+	}
+
+	t.Run("throws error on network failure", func(t *testing.T) {
+		fsys := afero.NewMemMapFs()
+		project := apitest.RandomProjectRef()
+		token := apitest.RandomAccessToken(t)
+		t.Setenv("SUPABASE_ACCESS_TOKEN", string(token))
+		defer gock.OffAll()
+		gock.New(utils.DefaultApiHost).
+			Get("/v1/projects/" + project + "/secrets").
+			Reply(http.StatusServiceUnavailable)
+		// Run test
+		err := Run(context.Background(), project, output.FormatTable, fsys)
+		// Check error
+		assert.ErrorContains(t, err, "Unexpected error retrieving project secrets:")
+	})
+}
@@ -0,0 +1,112 @@
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Format is the machine-readable output format requested via --output.
+type Format string
+
+const (
+	FormatTable Format = "table"
+	FormatJSON  Format = "json"
+	FormatYAML  Format = "yaml"
+	FormatCSV   Format = "csv"
+)
+
+// Set implements pflag.Value so Format can be bound directly to a flag.
+func (f *Format) Set(value string) error {
+	switch Format(value) {
+	case FormatTable, FormatJSON, FormatYAML, FormatCSV:
+		*f = Format(value)
+		return nil
+	default:
+		return fmt.Errorf("must be one of table, json, yaml, csv")
+	}
+}
+
+func (f Format) String() string {
+	if f == "" {
+		return string(FormatTable)
+	}
+	return string(f)
+}
+
+func (f Format) Type() string {
+	return "format"
+}
+
+// Write renders rows in the requested format to w. For FormatTable, renderTable
+// is called so each caller can keep its existing Markdown table layout; every
+// other format is derived generically from rows via its struct tags.
+func Write(w io.Writer, format Format, rows any, renderTable func() error) error {
+	switch format {
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(rows)
+	case FormatYAML:
+		enc := yaml.NewEncoder(w)
+		if err := enc.Encode(rows); err != nil {
+			return err
+		}
+		return enc.Close()
+	case FormatCSV:
+		return writeCSV(w, rows)
+	default:
+		return renderTable()
+	}
+}
+
+// writeCSV flattens rows (typically a slice of structs with json tags) into
+// a header row plus one row per record, so callers don't need to hand-roll
+// CSV writing for every new output.
+func writeCSV(w io.Writer, rows any) error {
+	data, err := json.Marshal(rows)
+	if err != nil {
+		return err
+	}
+	var records []map[string]any
+	if err := json.Unmarshal(data, &records); err != nil {
+		return err
+	}
+
+	cw := csv.NewWriter(w)
+	defer cw.Flush()
+	if len(records) == 0 {
+		return nil
+	}
+
+	keys := make(map[string]bool)
+	for _, rec := range records {
+		for k := range rec {
+			keys[k] = true
+		}
+	}
+	header := make([]string, 0, len(keys))
+	for k := range keys {
+		header = append(header, k)
+	}
+	sort.Strings(header)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, rec := range records {
+		row := make([]string, len(header))
+		for i, h := range header {
+			if v, ok := rec[h]; ok && v != nil {
+				row[i] = fmt.Sprintf("%v", v)
+			}
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
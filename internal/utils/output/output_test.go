@@ -0,0 +1,60 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testRow struct {
+	Name  string `json:"name"`
+	Value int    `json:"value"`
+}
+
+func TestWrite(t *testing.T) {
+	rows := []testRow{{Name: "a", Value: 1}, {Name: "b", Value: 2}}
+
+	tests := []struct {
+		format Format
+		want   string
+	}{
+		{FormatTable, "rendered table"},
+		{FormatJSON, "[\n  {\n    \"name\": \"a\",\n    \"value\": 1\n  },\n  {\n    \"name\": \"b\",\n    \"value\": 2\n  }\n]\n"},
+		{FormatYAML, "- name: a\n  value: 1\n- name: b\n  value: 2\n"},
+		{FormatCSV, "name,value\na,1\nb,2\n"},
+	}
+
+	for _, tc := range tests {
+		t.Run(string(tc.format), func(t *testing.T) {
+			var buf bytes.Buffer
+			err := Write(&buf, tc.format, rows, func() error {
+				_, err := buf.WriteString("rendered table")
+				return err
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, buf.String())
+		})
+	}
+}
+
+func TestWriteCSVUnionsHeadersAcrossRecords(t *testing.T) {
+	type sparseRow struct {
+		Slug  string `json:"slug"`
+		Error string `json:"error,omitempty"`
+	}
+	rows := []sparseRow{{Slug: "a"}, {Slug: "b", Error: "boom"}}
+
+	var buf bytes.Buffer
+	err := Write(&buf, FormatCSV, rows, func() error { return nil })
+	require.NoError(t, err)
+	assert.Equal(t, "error,slug\n,a\nboom,b\n", buf.String())
+}
+
+func TestFormatSet(t *testing.T) {
+	var f Format
+	assert.NoError(t, f.Set("json"))
+	assert.Equal(t, FormatJSON, f)
+	assert.ErrorContains(t, f.Set("xml"), "must be one of")
+}
@@ -0,0 +1,135 @@
+// Package loader resolves function sources — entrypoints and import maps —
+// that live outside the local supabase/functions directory, so deploys can
+// reference them by URL instead of only by filesystem path.
+package loader
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/afero"
+)
+
+// maxRedirects bounds how many hops a single fetch will follow before giving
+// up, guarding against redirect loops in misbehaving servers.
+const maxRedirects = 10
+
+var githubRefPattern = regexp.MustCompile(`^([^/]+)/([^@/]+)@([^/]+)/(.+)$`)
+
+// Resolve fetches the content at ref and returns it alongside the canonical
+// URL to report as entrypoint_path/import_map_path. ref may be a local
+// filesystem path, or a "file://", "https://", or "github://owner/repo@ref/path"
+// location. fsys backs both local reads and the HTTP response cache, so
+// tests can exercise Resolve hermetically against afero.NewMemMapFs() instead
+// of touching the real filesystem. The caller is responsible for closing the
+// returned reader.
+func Resolve(ctx context.Context, ref string, fsys afero.Fs) (io.ReadCloser, string, error) {
+	switch {
+	case strings.HasPrefix(ref, "github://"):
+		return resolveGithub(ctx, ref, fsys)
+	case strings.HasPrefix(ref, "https://"), strings.HasPrefix(ref, "http://"):
+		return resolveHTTP(ctx, ref, fsys)
+	case strings.HasPrefix(ref, "file://"):
+		path := strings.TrimPrefix(ref, "file://")
+		f, err := fsys.Open(path)
+		if err != nil {
+			return nil, "", err
+		}
+		return f, ref, nil
+	default:
+		abs, err := filepath.Abs(ref)
+		if err != nil {
+			return nil, "", err
+		}
+		f, err := fsys.Open(abs)
+		if err != nil {
+			return nil, "", err
+		}
+		return f, "file://" + abs, nil
+	}
+}
+
+// resolveGithub rewrites a github://owner/repo@ref/path location into a raw
+// content URL and delegates the actual fetch (and caching) to resolveHTTP,
+// while still reporting the github:// form as the canonical source.
+func resolveGithub(ctx context.Context, ref string, fsys afero.Fs) (io.ReadCloser, string, error) {
+	rest := strings.TrimPrefix(ref, "github://")
+	m := githubRefPattern.FindStringSubmatch(rest)
+	if m == nil {
+		return nil, "", fmt.Errorf("invalid github ref %q, expected github://owner/repo@ref/path", ref)
+	}
+	owner, repo, gitRef, path := m[1], m[2], m[3], m[4]
+	rawUrl := fmt.Sprintf("https://raw.githubusercontent.com/%s/%s/%s/%s", owner, repo, gitRef, path)
+	body, _, err := resolveHTTP(ctx, rawUrl, fsys)
+	if err != nil {
+		return nil, "", err
+	}
+	return body, ref, nil
+}
+
+// resolveHTTP fetches ref over HTTP(S), caching the response body under
+// ~/.cache/supabase/deploy/<sha256 of ref> (on fsys) so repeated deploys of
+// the same ref don't require network access.
+func resolveHTTP(ctx context.Context, ref string, fsys afero.Fs) (io.ReadCloser, string, error) {
+	cachePath, err := cachePathFor(ref)
+	if err != nil {
+		return nil, "", err
+	}
+	if f, err := fsys.Open(cachePath); err == nil {
+		return f, ref, nil
+	}
+
+	client := &http.Client{
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxRedirects)
+			}
+			return nil
+		},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ref, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("failed to fetch %s: %s", ref, resp.Status)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if err := fsys.MkdirAll(filepath.Dir(cachePath), 0755); err != nil {
+		return nil, "", err
+	}
+	if err := afero.WriteFile(fsys, cachePath, data, 0644); err != nil {
+		return nil, "", err
+	}
+	f, err := fsys.Open(cachePath)
+	if err != nil {
+		return nil, "", err
+	}
+	return f, ref, nil
+}
+
+func cachePathFor(ref string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(ref))
+	return filepath.Join(home, ".cache", "supabase", "deploy", hex.EncodeToString(sum[:])), nil
+}
@@ -0,0 +1,113 @@
+package loader
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestResolveHTTP(t *testing.T) {
+	t.Run("fetches and caches a remote entrypoint", func(t *testing.T) {
+		ref := "https://example.com/deploy-test/index.ts"
+		fsys := afero.NewMemMapFs()
+		cachePath, err := cachePathFor(ref)
+		require.NoError(t, err)
+
+		defer gock.OffAll()
+		gock.New("https://example.com").
+			Get("/deploy-test/index.ts").
+			Reply(http.StatusOK).
+			BodyString("console.log('hello')")
+
+		body, canonical, err := Resolve(context.Background(), ref, fsys)
+		require.NoError(t, err)
+		defer body.Close()
+		data, err := io.ReadAll(body)
+		require.NoError(t, err)
+		assert.Equal(t, "console.log('hello')", string(data))
+		assert.Equal(t, ref, canonical)
+		exists, err := afero.Exists(fsys, cachePath)
+		require.NoError(t, err)
+		assert.True(t, exists)
+	})
+
+	t.Run("serves subsequent fetches from cache without hitting the network", func(t *testing.T) {
+		ref := "https://example.com/deploy-test/cached.ts"
+		fsys := afero.NewMemMapFs()
+		cachePath, err := cachePathFor(ref)
+		require.NoError(t, err)
+		require.NoError(t, fsys.MkdirAll(filepath.Dir(cachePath), 0755))
+		require.NoError(t, afero.WriteFile(fsys, cachePath, []byte("cached content"), 0644))
+
+		defer gock.OffAll()
+		body, canonical, err := Resolve(context.Background(), ref, fsys)
+		require.NoError(t, err)
+		defer body.Close()
+		data, err := io.ReadAll(body)
+		require.NoError(t, err)
+		assert.Equal(t, "cached content", string(data))
+		assert.Equal(t, ref, canonical)
+	})
+
+	t.Run("throws error on non-200 response", func(t *testing.T) {
+		ref := "https://example.com/deploy-test/missing.ts"
+		fsys := afero.NewMemMapFs()
+
+		defer gock.OffAll()
+		gock.New("https://example.com").
+			Get("/deploy-test/missing.ts").
+			Reply(http.StatusNotFound)
+
+		_, _, err := Resolve(context.Background(), ref, fsys)
+		assert.Error(t, err)
+	})
+}
+
+func TestResolveGithub(t *testing.T) {
+	t.Run("rewrites a github ref to a raw content URL", func(t *testing.T) {
+		ref := "github://supabase/cli@v1.0.0/functions/hello/index.ts"
+		fsys := afero.NewMemMapFs()
+
+		defer gock.OffAll()
+		gock.New("https://raw.githubusercontent.com").
+			Get("/supabase/cli/v1.0.0/functions/hello/index.ts").
+			Reply(http.StatusOK).
+			BodyString("export default () => new Response('hi')")
+
+		body, canonical, err := Resolve(context.Background(), ref, fsys)
+		require.NoError(t, err)
+		defer body.Close()
+		data, err := io.ReadAll(body)
+		require.NoError(t, err)
+		assert.Equal(t, "export default () => new Response('hi')", string(data))
+		assert.Equal(t, ref, canonical, "canonical URL should be the github:// form, not the raw URL")
+	})
+
+	t.Run("throws error on malformed ref", func(t *testing.T) {
+		_, _, err := Resolve(context.Background(), "github://supabase-cli-missing-at-sign", afero.NewMemMapFs())
+		assert.ErrorContains(t, err, "invalid github ref")
+	})
+}
+
+func TestResolveLocal(t *testing.T) {
+	t.Run("opens a file:// ref directly", func(t *testing.T) {
+		fsys := afero.NewMemMapFs()
+		path := "/src/index.ts"
+		require.NoError(t, afero.WriteFile(fsys, path, []byte("local content"), 0644))
+
+		body, canonical, err := Resolve(context.Background(), "file://"+path, fsys)
+		require.NoError(t, err)
+		defer body.Close()
+		data, err := io.ReadAll(body)
+		require.NoError(t, err)
+		assert.Equal(t, "local content", string(data))
+		assert.Equal(t, "file://"+path, canonical)
+	})
+}